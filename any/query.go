@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"sort"
 	"strconv"
 )
 
@@ -130,6 +132,110 @@ func (q *Query) Int() (i int) {
 	return
 }
 
+// ToInt converts the value to an int. The value must not have a fractional
+// part and must fit into an int. Integer types, json.Number and strings with
+// an integer are converted exactly.
+func (q *Query) ToInt() (i int) {
+	// previous error or empty?
+	if q.Err() != nil || q.value == nil {
+		return
+	}
+	// convert value
+	switch v := q.value.(type) {
+	case int:
+		i = v
+	case int64:
+		i = q.int64ToInt(v)
+	case int32:
+		i = q.int64ToInt(int64(v))
+	case int16:
+		i = int(v)
+	case int8:
+		i = int(v)
+	case uint:
+		i = q.uint64ToInt(uint64(v))
+	case uint64:
+		i = q.uint64ToInt(v)
+	case uint32:
+		i = q.uint64ToInt(uint64(v))
+	case uint16:
+		i = int(v)
+	case uint8:
+		i = int(v)
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			i = q.int64ToInt(n)
+		} else {
+			i = q.float64ToInt()
+		}
+	case string:
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			i = q.int64ToInt(n)
+		} else {
+			i = q.float64ToInt()
+		}
+	default:
+		i = q.float64ToInt()
+	}
+	return
+}
+
+const maxInt = int(^uint(0) >> 1)
+
+func (q *Query) int64ToInt(n int64) int {
+	if int64(int(n)) != n {
+		*q.err = fmt.Errorf("unable to cast %#v of type %T to int", q.value, q.value)
+		return 0
+	}
+	return int(n)
+}
+
+func (q *Query) uint64ToInt(n uint64) int {
+	if n > uint64(maxInt) {
+		*q.err = fmt.Errorf("unable to cast %#v of type %T to int", q.value, q.value)
+		return 0
+	}
+	return int(n)
+}
+
+// float64ToInt converts the value with ToFloat64 to an int (e.g. for floats,
+// bools and strings like "2.0").
+func (q *Query) float64ToInt() int {
+	f := q.ToFloat64()
+	if q.Err() != nil {
+		return 0
+	}
+	if f != math.Trunc(f) || float64(int(f)) != f {
+		*q.err = fmt.Errorf("unable to cast %#v of type %T to int", q.value, q.value)
+		return 0
+	}
+	return int(f)
+}
+
+// ToString converts the value to a string.
+func (q *Query) ToString() (s string) {
+	// previous error or empty?
+	if q.Err() != nil || q.value == nil {
+		return
+	}
+	// convert value
+	switch v := q.value.(type) {
+	case string:
+		s = v
+	case json.Number:
+		s = string(v)
+	case float64:
+		s = strconv.FormatFloat(v, 'g', -1, 64)
+	case float32:
+		s = strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case int, int64, int32, int16, int8, uint, uint64, uint32, uint16, uint8, bool:
+		s = fmt.Sprint(v)
+	default:
+		*q.err = fmt.Errorf("unable to cast %#v of type %T to string", q.value, q.value)
+	}
+	return
+}
+
 // String gets a string.
 func (q *Query) String() (s string) {
 	// previous error or empty?
@@ -164,6 +270,57 @@ func (q *Query) Slice() []*Query {
 	return ns
 }
 
+// StringSlice converts a []interface{} to a []string. The elements are
+// converted with ToString.
+func (q *Query) StringSlice() []string {
+	qs := q.Slice()
+	if q.Err() != nil || qs == nil {
+		return nil
+	}
+	r := make([]string, len(qs))
+	for i, e := range qs {
+		r[i] = e.ToString()
+		if q.Err() != nil {
+			return nil
+		}
+	}
+	return r
+}
+
+// Float64Slice converts a []interface{} to a []float64. The elements are
+// converted with ToFloat64.
+func (q *Query) Float64Slice() []float64 {
+	qs := q.Slice()
+	if q.Err() != nil || qs == nil {
+		return nil
+	}
+	r := make([]float64, len(qs))
+	for i, e := range qs {
+		r[i] = e.ToFloat64()
+		if q.Err() != nil {
+			return nil
+		}
+	}
+	return r
+}
+
+// IntSlice converts a []interface{} to a []int. The elements are converted
+// with ToInt.
+func (q *Query) IntSlice() []int {
+	qs := q.Slice()
+	if q.Err() != nil || qs == nil {
+		return nil
+	}
+	r := make([]int, len(qs))
+	for i, e := range qs {
+		r[i] = e.ToInt()
+		if q.Err() != nil {
+			return nil
+		}
+	}
+	return r
+}
+
 // Map gets a map[string]interface{} as MapQuery.
 func (q *Query) Map() *MapQuery {
 	// previous error or empty?
@@ -232,6 +389,32 @@ func (q *MapQuery) Has(name string) bool {
 	return ok
 }
 
+// Keys returns the keys of the map in sorted order.
+func (q *MapQuery) Keys() []string {
+	// previous error?
+	if q.Err() != nil {
+		return nil
+	}
+	// collect and sort keys
+	ks := make([]string, 0, len(q.value))
+	for k := range q.value {
+		ks = append(ks, k)
+	}
+	sort.Strings(ks)
+	return ks
+}
+
+// Each calls the specified function for each map entry in sorted key order.
+// If the function returns an error, the iteration stops and the error is set.
+func (q *MapQuery) Each(f func(key string, q *Query) error) {
+	for _, k := range q.Keys() {
+		if err := f(k, &Query{value: q.value[k], err: q.err}); err != nil {
+			*q.err = err
+			return
+		}
+	}
+}
+
 // Wrap returns a new map with all values wrapped as Query.
 func (q *MapQuery) Wrap() map[string]*Query {
 	// previous error?
@@ -247,6 +430,38 @@ func (q *MapQuery) Wrap() map[string]*Query {
 	return r
 }
 
+// Merge returns a new map with the entries of both maps. Nested maps are merged
+// recursively, other values of the other map take precedence. Both maps are
+// not modified. An error of the other map is taken over.
+func (q *MapQuery) Merge(other *MapQuery) *MapQuery {
+	// previous error?
+	if q.Err() == nil && other.Err() != nil {
+		*q.err = other.Err()
+	}
+	if q.Err() != nil {
+		return &MapQuery{err: q.err}
+	}
+	return &MapQuery{value: mergeMaps(q.value, other.value), err: q.err}
+}
+
+func mergeMaps(a, b map[string]interface{}) map[string]interface{} {
+	r := make(map[string]interface{}, len(a)+len(b))
+	for k, v := range a {
+		r[k] = v
+	}
+	for k, bv := range b {
+		// merge nested maps
+		if bm, ok := bv.(map[string]interface{}); ok {
+			if am, ok := r[k].(map[string]interface{}); ok {
+				r[k] = mergeMaps(am, bm)
+				continue
+			}
+		}
+		r[k] = bv
+	}
+	return r
+}
+
 // Wrap returns a new map with all values wrapped as Query.
 func (q *MapQuery) Unwrap() map[string]interface{} {
 	// nil on previous error
@@ -2,6 +2,9 @@ package any
 
 import (
 	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
 	"testing"
 )
 
@@ -90,6 +93,87 @@ func TestMapWrap(t *testing.T) {
 	}
 }
 
+func TestMapEach(t *testing.T) {
+	var v interface{} = map[string]interface{}{"c": 3.0, "a": 1.0, "d": 4.0, "b": 2.0}
+	q := Q(v)
+	m := q.Map()
+	if ks := m.Keys(); !reflect.DeepEqual(ks, []string{"a", "b", "c", "d"}) {
+		t.Error(ks)
+	}
+	var ks []string
+	var fs []float64
+	m.Each(func(k string, e *Query) error {
+		ks = append(ks, k)
+		fs = append(fs, e.Float64())
+		return nil
+	})
+	if q.Err() != nil {
+		t.Error(q.Err())
+	}
+	if !reflect.DeepEqual(ks, []string{"a", "b", "c", "d"}) {
+		t.Error(ks)
+	}
+	if !reflect.DeepEqual(fs, []float64{1, 2, 3, 4}) {
+		t.Error(fs)
+	}
+	ks = nil
+	m.Each(func(k string, e *Query) error {
+		ks = append(ks, k)
+		if k == "b" {
+			return errors.New("stop")
+		}
+		return nil
+	})
+	if q.Err() == nil || q.Err().Error() != "stop" {
+		t.Error(q.Err())
+	}
+	if !reflect.DeepEqual(ks, []string{"a", "b"}) {
+		t.Error(ks)
+	}
+	if m.Keys() != nil {
+		t.Error("expected nil on previous error")
+	}
+}
+
+func TestMapMerge(t *testing.T) {
+	a := map[string]interface{}{
+		"a": 1.0,
+		"b": map[string]interface{}{"c": "x", "d": true},
+		"e": map[string]interface{}{"f": 2.0},
+	}
+	b := map[string]interface{}{
+		"a": "y",
+		"b": map[string]interface{}{"d": false, "g": 3.0},
+		"e": 4.0,
+		"h": map[string]interface{}{"i": 5.0},
+	}
+	q := Q(a)
+	m := q.Map().Merge(Q(b).Map())
+	if q.Err() != nil {
+		t.Fatal(q.Err())
+	}
+	exp := map[string]interface{}{
+		"a": "y",
+		"b": map[string]interface{}{"c": "x", "d": false, "g": 3.0},
+		"e": 4.0,
+		"h": map[string]interface{}{"i": 5.0},
+	}
+	if !reflect.DeepEqual(m.Unwrap(), exp) {
+		t.Error(m.Unwrap())
+	}
+	// inputs are not modified
+	if !reflect.DeepEqual(a["b"], map[string]interface{}{"c": "x", "d": true}) {
+		t.Error(a["b"])
+	}
+
+	// error of other map
+	q = Q(a)
+	m = q.Map().Merge(Q("abc").Map())
+	if q.Err() == nil || m.Unwrap() != nil {
+		t.Error("expected error")
+	}
+}
+
 func TestSliceQuery(t *testing.T) {
 	var v interface{} = []interface{}{"a", 123.456, "b", true}
 	q := Q(v)
@@ -156,3 +240,69 @@ func TestQueryToFloat64(t *testing.T) {
 		}
 	}
 }
+
+func TestQueryTypedSlices(t *testing.T) {
+	q := Q([]interface{}{"a", "b", "c"})
+	if s := q.StringSlice(); q.Err() != nil || !reflect.DeepEqual(s, []string{"a", "b", "c"}) {
+		t.Error(s, q.Err())
+	}
+	q = Q([]interface{}{1.5, 2.0, 3.25})
+	if f := q.Float64Slice(); q.Err() != nil || !reflect.DeepEqual(f, []float64{1.5, 2, 3.25}) {
+		t.Error(f, q.Err())
+	}
+	q = Q([]interface{}{1.0, 2.0, 3.0})
+	if i := q.IntSlice(); q.Err() != nil || !reflect.DeepEqual(i, []int{1, 2, 3}) {
+		t.Error(i, q.Err())
+	}
+
+	// mixed types with lenient conversions
+	q = Q([]interface{}{"a", 1.5, json.Number("7"), true})
+	if s := q.StringSlice(); q.Err() != nil || !reflect.DeepEqual(s, []string{"a", "1.5", "7", "true"}) {
+		t.Error(s, q.Err())
+	}
+	q = Q([]interface{}{1, "2.5", json.Number("3"), false})
+	if f := q.Float64Slice(); q.Err() != nil || !reflect.DeepEqual(f, []float64{1, 2.5, 3, 0}) {
+		t.Error(f, q.Err())
+	}
+	q = Q([]interface{}{1, "2", json.Number("3"), true})
+	if i := q.IntSlice(); q.Err() != nil || !reflect.DeepEqual(i, []int{1, 2, 3, 1}) {
+		t.Error(i, q.Err())
+	}
+
+	// large integers are converted exactly
+	if strconv.IntSize == 64 {
+		big := int64(9007199254740993)
+		q = Q([]interface{}{json.Number("9007199254740993"), big,
+			uint64(big), "9007199254740993", json.Number("2.0")})
+		exp := []int{int(big), int(big), int(big), int(big), 2}
+		if i := q.IntSlice(); q.Err() != nil || !reflect.DeepEqual(i, exp) {
+			t.Error(i, q.Err())
+		}
+	}
+	q = Q([]interface{}{uint64(1 << 63)})
+	if i := q.IntSlice(); i != nil || q.Err() == nil || q.Err().Error() != "unable to cast 0x8000000000000000 of type uint64 to int" {
+		t.Error(i, q.Err())
+	}
+	q = Q([]interface{}{json.Number("1e30")})
+	if i := q.IntSlice(); i != nil || q.Err() == nil {
+		t.Error(i, q.Err())
+	}
+
+	// conversion errors
+	q = Q([]interface{}{1.0, 2.5})
+	if i := q.IntSlice(); i != nil || q.Err() == nil || q.Err().Error() != "unable to cast 2.5 of type float64 to int" {
+		t.Error(i, q.Err())
+	}
+	q = Q([]interface{}{"a", []interface{}{}})
+	if s := q.StringSlice(); s != nil || q.Err() == nil {
+		t.Error(s, q.Err())
+	}
+	q = Q([]interface{}{1.0, "x"})
+	if f := q.Float64Slice(); f != nil || q.Err() == nil {
+		t.Error(f, q.Err())
+	}
+	q = Q("abc")
+	if f := q.Float64Slice(); f != nil || q.Err() == nil || q.Err().Error() != "not a slice" {
+		t.Error(f, q.Err())
+	}
+}
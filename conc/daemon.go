@@ -1,11 +1,23 @@
 package conc
 
-import "sync"
+import (
+	"fmt"
+	"sync"
+	"time"
+)
 
 // DaemonFunc runs the specified function as daemon. Calling the returned
 // function signals that the daemon function should be canceled and waits until
 // the daemon function returns.
 func DaemonFunc(f func(Context)) func() {
+	return DaemonFuncWithDrain(f, nil)
+}
+
+// DaemonFuncWithDrain runs the specified function as daemon. Calling the
+// returned function signals that the daemon function should be canceled, then
+// calls the drain function (e.g. for flushing buffers), if not nil, and waits
+// until the daemon function returns.
+func DaemonFuncWithDrain(f func(Context), drain func()) func() {
 	// size of 1 does not block go routine, if the function is completed before
 	// it is cancelled.
 	term := make(chan struct{}, 1)
@@ -18,16 +30,52 @@ func DaemonFunc(f func(Context)) func() {
 	}()
 	return func() {
 		close(ctx.done)
+		if drain != nil {
+			drain()
+		}
 		<-term
 	}
 }
 
+// MinSuperviseBackoff is the minimum backoff duration of Supervise.
+const MinSuperviseBackoff = 10 * time.Millisecond
+
+// Supervise runs the specified function as daemon. If the function returns an
+// error, it is restarted after waiting the backoff duration (at least
+// MinSuperviseBackoff). The error is not reported, the function should log it
+// itself. The supervision ends, when the function returns nil or the daemon is
+// canceled. Calling the returned function signals that the function should be
+// canceled and waits until it returns.
+func Supervise(f func(Context) error, backoff time.Duration) func() {
+	if backoff < MinSuperviseBackoff {
+		backoff = MinSuperviseBackoff
+	}
+	return DaemonFunc(func(ctx Context) {
+		for {
+			err := f(ctx)
+			if err == nil || ctx.IsDone() {
+				return
+			}
+			// wait before restart
+			if ctx.Sleep(backoff) == ErrCanceled {
+				return
+			}
+		}
+	})
+}
+
 // DaemonPool runs any number of functions as daemon. All functions can be
-// canceled simultaneously with Close.
+// canceled simultaneously with Close or CloseTimeout.
 type DaemonPool struct {
-	once sync.Once
-	ctx  *context
-	wg   sync.WaitGroup
+	once      sync.Once
+	closeOnce sync.Once
+	idleOnce  sync.Once
+	ctx       *context
+	wg        sync.WaitGroup
+	mtx       sync.Mutex
+	running   int
+	// closed, when the pool is canceled and no daemon function is running
+	idle chan struct{}
 }
 
 // Run runs the specified function as daemon. The specified function can spawn
@@ -35,24 +83,77 @@ type DaemonPool struct {
 func (d *DaemonPool) Run(f func(Context)) {
 	d.init()
 	d.wg.Add(1)
+	d.mtx.Lock()
+	d.running++
+	d.mtx.Unlock()
 	go func() {
 		defer d.wg.Done()
+		defer d.returned()
 		f(d.ctx)
 	}()
 }
 
 // Close signals to all running daemon functions that they should be canceled
-// and waits until all daemon functions return.
+// and waits until all daemon functions return. Close can be called multiple
+// times.
 func (d *DaemonPool) Close() {
 	d.init()
-	close(d.ctx.done)
+	d.cancel()
 	d.wg.Wait()
 }
 
+// CloseTimeout signals to all running daemon functions that they should be
+// canceled and waits at most the specified duration until all daemon functions
+// return. If the timeout elapses, an error with the number of still running
+// daemon functions is returned. These daemon functions are left running, but
+// no additional go routine is left behind. CloseTimeout can be called multiple
+// times and after Close.
+func (d *DaemonPool) CloseTimeout(timeout time.Duration) error {
+	d.init()
+	d.cancel()
+	t := time.NewTimer(timeout)
+	select {
+	case <-d.idle:
+		t.Stop()
+		return nil
+	case <-t.C:
+		d.mtx.Lock()
+		defer d.mtx.Unlock()
+		return fmt.Errorf("Timeout while closing daemon pool, still running: %d", d.running)
+	}
+}
+
 func (d *DaemonPool) init() {
 	d.once.Do(func() {
 		d.ctx = &context{
 			done: make(chan struct{}),
 		}
+		d.idle = make(chan struct{})
+	})
+}
+
+// cancel signals cancellation to the daemon functions only once.
+func (d *DaemonPool) cancel() {
+	d.closeOnce.Do(func() {
+		close(d.ctx.done)
+		d.mtx.Lock()
+		defer d.mtx.Unlock()
+		d.signalIdle()
 	})
 }
+
+// returned is called, when a daemon function returns.
+func (d *DaemonPool) returned() {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	d.running--
+	d.signalIdle()
+}
+
+// signalIdle closes the idle channel, if the pool is canceled and no daemon
+// function is running. The mutex must be locked.
+func (d *DaemonPool) signalIdle() {
+	if d.running == 0 && d.ctx.IsDone() {
+		d.idleOnce.Do(func() { close(d.idle) })
+	}
+}
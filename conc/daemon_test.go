@@ -1,7 +1,9 @@
 package conc
 
 import (
+	"errors"
 	"reflect"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -40,6 +42,65 @@ func TestDaemonFunc(t *testing.T) {
 	}
 }
 
+func TestDaemonFuncWithDrain(t *testing.T) {
+	started := make(chan Context, 1)
+	drained := make(chan struct{})
+	var ctx Context
+	c := DaemonFuncWithDrain(func(c Context) {
+		started <- c
+		<-c.Done()
+	}, func() {
+		// cancellation is already signaled
+		if !ctx.IsDone() {
+			t.Error("expected canceled context")
+		}
+		close(drained)
+	})
+	ctx = <-started
+	c()
+	// drain is completed, when the cancel function returns
+	select {
+	case <-drained:
+	default:
+		t.Fatal("drain not completed")
+	}
+}
+
+func TestSupervise(t *testing.T) {
+	l := []int{}
+	up := make(chan struct{})
+	c := Supervise(func(ctx Context) error {
+		l = append(l, len(l))
+		if len(l) < 3 {
+			return errors.New("failed")
+		}
+		up <- struct{}{}
+		<-ctx.Done()
+		l = append(l, -1)
+		return nil
+	}, 10*time.Millisecond)
+	<-up
+	time.Sleep(100 * time.Millisecond)
+	c()
+	if !reflect.DeepEqual(l, []int{0, 1, 2, -1}) {
+		t.Fatal(l)
+	}
+}
+
+func TestSuperviseMinBackoff(t *testing.T) {
+	var cnt int32
+	c := Supervise(func(ctx Context) error {
+		atomic.AddInt32(&cnt, 1)
+		return errors.New("failed")
+	}, 0)
+	time.Sleep(100 * time.Millisecond)
+	c()
+	// no busy loop
+	if n := atomic.LoadInt32(&cnt); n > int32(100*time.Millisecond/MinSuperviseBackoff)+1 {
+		t.Fatal(n)
+	}
+}
+
 func TestDaemonPool(t *testing.T) {
 	p := &DaemonPool{}
 	l := []int{}
@@ -65,3 +126,45 @@ func TestDaemonPoolNoRun(t *testing.T) {
 	p := &DaemonPool{}
 	p.Close()
 }
+
+func TestDaemonPoolCloseTimeout(t *testing.T) {
+	p := &DaemonPool{}
+	p.Run(func(ctx Context) {
+		<-ctx.Done()
+	})
+	if err := p.CloseTimeout(time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	p = &DaemonPool{}
+	release := make(chan struct{})
+	p.Run(func(ctx Context) {
+		<-ctx.Done()
+	})
+	p.Run(func(Context) {
+		// ignores cancel
+		<-release
+	})
+	time.Sleep(100 * time.Millisecond)
+	err := p.CloseTimeout(100 * time.Millisecond)
+	if err == nil || err.Error() != "Timeout while closing daemon pool, still running: 1" {
+		t.Fatal(err)
+	}
+	// repeated close must not panic
+	err = p.CloseTimeout(10 * time.Millisecond)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	close(release)
+	if err = p.CloseTimeout(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	p.Close()
+
+	// after Close
+	p = &DaemonPool{}
+	p.Close()
+	if err = p.CloseTimeout(10 * time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -5,6 +5,89 @@ import (
 	"time"
 )
 
+// Debouncer calls a function after a burst of trigger calls. With Trailing
+// set, the function is called after no trigger call happened for the Wait
+// duration. With Leading set, the function is called immediately on the first
+// trigger call of a burst. If both are set, the trailing call only happens, if
+// the function was triggered again during the burst. MaxWait limits the
+// duration of a burst, if greater than zero (e.g. MaxWait equal to Wait
+// results in a throttled function). A Debouncer must not be copied after first
+// use.
+type Debouncer struct {
+	Wait     time.Duration
+	MaxWait  time.Duration
+	Leading  bool
+	Trailing bool
+	Func     func()
+
+	mtx     sync.Mutex
+	tmr     *time.Timer
+	gen     int
+	start   time.Time
+	pending bool
+}
+
+// Trigger starts or extends a burst. A leading call of the function is
+// executed by the calling go routine.
+func (d *Debouncer) Trigger() {
+	d.mtx.Lock()
+	now := time.Now()
+	leading := false
+	if d.tmr == nil {
+		// start of a burst
+		d.start = now
+		leading = d.Leading
+		d.pending = !leading
+	} else {
+		d.tmr.Stop()
+		d.pending = true
+	}
+	// calculate end of burst
+	wait := d.Wait
+	if d.MaxWait > 0 {
+		if rem := d.MaxWait - now.Sub(d.start); rem < wait {
+			wait = rem
+		}
+	}
+	// a generation counter discards timers, which fired while the mutex was
+	// locked
+	d.gen++
+	gen := d.gen
+	d.tmr = time.AfterFunc(wait, func() { d.fire(gen) })
+	d.mtx.Unlock()
+	if leading {
+		d.Func()
+	}
+}
+
+// Cancel discards a pending trailing call and ends the current burst.
+func (d *Debouncer) Cancel() {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	if d.tmr != nil {
+		d.tmr.Stop()
+		d.tmr = nil
+	}
+	d.gen++
+	d.pending = false
+}
+
+func (d *Debouncer) fire(gen int) {
+	d.mtx.Lock()
+	if gen != d.gen {
+		d.mtx.Unlock()
+		return
+	}
+	// end of burst
+	call := d.pending && d.Trailing
+	d.tmr = nil
+	d.pending = false
+	d.mtx.Unlock()
+	if call {
+		d.Func()
+	}
+}
+
 // DebouncedFunc waits the specified amount of time after a trigger call and
 // then calls the specified function. Further trigger calls while waiting are
 // discarded and restart the time span. A DebouncedFunc must not be copied after
@@ -13,26 +96,26 @@ type DebouncedFunc struct {
 	Dur  time.Duration
 	Func func()
 
-	tmr *time.Timer
-	mtx sync.Mutex
+	once sync.Once
+	deb  Debouncer
 }
 
+// Trigger starts or restarts the time span.
 func (d *DebouncedFunc) Trigger() {
-	d.mtx.Lock()
-	defer d.mtx.Unlock()
-	if d.tmr != nil {
-		d.tmr.Reset(d.Dur)
-	} else {
-		d.tmr = time.AfterFunc(d.Dur, d.Func)
-	}
+	d.init()
+	d.deb.Trigger()
 }
 
+// Cancel discards a pending call of the function.
 func (d *DebouncedFunc) Cancel() {
-	d.mtx.Lock()
-	defer d.mtx.Unlock()
-	if d.tmr != nil {
-		if !d.tmr.Stop() {
-			<-d.tmr.C
-		}
-	}
+	d.init()
+	d.deb.Cancel()
+}
+
+func (d *DebouncedFunc) init() {
+	d.once.Do(func() {
+		d.deb.Wait = d.Dur
+		d.deb.Trailing = true
+		d.deb.Func = d.Func
+	})
 }
@@ -37,3 +37,65 @@ func TestDebounceFunc(t *testing.T) {
 		t.Fatal()
 	}
 }
+
+func TestDebouncer(t *testing.T) {
+	cases := []struct {
+		leading, trailing bool
+		single, burst     int32
+	}{
+		{false, false, 0, 0},
+		{false, true, 1, 1},
+		{true, false, 1, 1},
+		{true, true, 1, 2},
+	}
+	for _, c := range cases {
+		var cnt int32
+		d := &Debouncer{
+			Wait:     50 * time.Millisecond,
+			Leading:  c.leading,
+			Trailing: c.trailing,
+			Func: func() {
+				atomic.AddInt32(&cnt, 1)
+			},
+		}
+
+		// single trigger
+		d.Trigger()
+		time.Sleep(100 * time.Millisecond)
+		if n := atomic.LoadInt32(&cnt); n != c.single {
+			t.Errorf("leading %t, trailing %t: single: %d", c.leading, c.trailing, n)
+		}
+
+		// burst of triggers
+		atomic.StoreInt32(&cnt, 0)
+		for i := 0; i < 5; i++ {
+			d.Trigger()
+			time.Sleep(10 * time.Millisecond)
+		}
+		time.Sleep(100 * time.Millisecond)
+		if n := atomic.LoadInt32(&cnt); n != c.burst {
+			t.Errorf("leading %t, trailing %t: burst: %d", c.leading, c.trailing, n)
+		}
+	}
+}
+
+func TestDebouncerMaxWait(t *testing.T) {
+	var cnt int32
+	d := &Debouncer{
+		Wait:     50 * time.Millisecond,
+		MaxWait:  100 * time.Millisecond,
+		Trailing: true,
+		Func: func() {
+			atomic.AddInt32(&cnt, 1)
+		},
+	}
+	// trigger for 250ms, without MaxWait no call would happen
+	for i := 0; i < 25; i++ {
+		d.Trigger()
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := atomic.LoadInt32(&cnt); n < 2 {
+		t.Fatal(n)
+	}
+	d.Cancel()
+}
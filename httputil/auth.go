@@ -1,7 +1,9 @@
 package httputil
 
 import (
+	"crypto/subtle"
 	"net/http"
+	"strings"
 
 	"github.com/mdzio/go-logging"
 )
@@ -10,6 +12,9 @@ var (
 	log = logging.Get("auth-handler")
 )
 
+// dummyPassword is compared for unknown users.
+const dummyPassword = "c3a8f0e2b7d94e1fa6b5c0d8e2f4a9b1"
+
 // SingleAuthHandler wraps another http.Handler and forces the specified
 // authentication from the HTTP client.
 type SingleAuthHandler struct {
@@ -50,6 +55,126 @@ func (h *SingleAuthHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request)
 }
 
 func (h *SingleAuthHandler) sendAuth(rw http.ResponseWriter, _ *http.Request) {
-	rw.Header().Set("WWW-Authenticate", "Basic realm=\""+h.Realm+"\", charset=\"UTF-8\"")
+	sendBasicAuth(rw, h.Realm)
+}
+
+// MultiAuthHandler wraps another http.Handler and forces a basic
+// authentication with one of several users from the HTTP client.
+type MultiAuthHandler struct {
+	http.Handler
+
+	// Users maps user names to passwords.
+	Users map[string]string
+
+	// If CredentialChecker is not nil, it is used instead of Users for checking
+	// the credentials.
+	CredentialChecker func(user, password string) bool
+
+	// Realm must only contain valid characters for an HTTP header value and no
+	// double quotes.
+	Realm string
+}
+
+func (h *MultiAuthHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	user, passwd, ok := req.BasicAuth()
+
+	// no credentials
+	if !ok {
+		log.Tracef("Not authenticated: %s", req.RemoteAddr)
+		sendBasicAuth(rw, h.Realm)
+		return
+	}
+
+	// check credentials
+	if h.CredentialChecker != nil {
+		if !h.CredentialChecker(user, passwd) {
+			log.Warningf("Invalid credentials for user %s: %s", user, req.RemoteAddr)
+			sendBasicAuth(rw, h.Realm)
+			return
+		}
+	} else {
+		expected, ok := h.Users[user]
+		if !ok {
+			// compare anyway, the response time should not reveal whether the
+			// user exists
+			subtle.ConstantTimeCompare([]byte(passwd), []byte(dummyPassword))
+			log.Warningf("Unknown user %s: %s", user, req.RemoteAddr)
+			sendBasicAuth(rw, h.Realm)
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(passwd), []byte(expected)) != 1 {
+			log.Warningf("Invalid password for user %s: %s", user, req.RemoteAddr)
+			sendBasicAuth(rw, h.Realm)
+			return
+		}
+	}
+
+	// credentials ok
+	h.Handler.ServeHTTP(rw, req)
+}
+
+// BearerAuthHandler wraps another http.Handler and forces a bearer token
+// authentication (Authorization: Bearer <token>) from the HTTP client.
+type BearerAuthHandler struct {
+	http.Handler
+
+	// Tokens contains the valid tokens.
+	Tokens []string
+
+	// If Validate is not nil, it is used instead of Tokens for checking the
+	// token.
+	Validate func(token string) bool
+
+	// Realm is optional and must only contain valid characters for an HTTP
+	// header value and no double quotes.
+	Realm string
+}
+
+func (h *BearerAuthHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	const prefix = "bearer "
+	auth := req.Header.Get("Authorization")
+
+	// no token
+	if len(auth) < len(prefix) || strings.ToLower(auth[:len(prefix)]) != prefix {
+		log.Tracef("Not authenticated: %s", req.RemoteAddr)
+		h.sendAuth(rw)
+		return
+	}
+
+	// check token
+	token := strings.TrimSpace(auth[len(prefix):])
+	if !h.valid(token) {
+		log.Warningf("Invalid bearer token: %s", req.RemoteAddr)
+		h.sendAuth(rw)
+		return
+	}
+
+	// token ok
+	h.Handler.ServeHTTP(rw, req)
+}
+
+func (h *BearerAuthHandler) valid(token string) bool {
+	if h.Validate != nil {
+		return h.Validate(token)
+	}
+	// compare with all tokens to avoid timing leaks
+	ok := 0
+	for _, t := range h.Tokens {
+		ok |= subtle.ConstantTimeCompare([]byte(token), []byte(t))
+	}
+	return token != "" && ok == 1
+}
+
+func (h *BearerAuthHandler) sendAuth(rw http.ResponseWriter) {
+	if h.Realm != "" {
+		rw.Header().Set("WWW-Authenticate", "Bearer realm=\""+h.Realm+"\"")
+	} else {
+		rw.Header().Set("WWW-Authenticate", "Bearer")
+	}
+	http.Error(rw, "Unauthorized", http.StatusUnauthorized)
+}
+
+func sendBasicAuth(rw http.ResponseWriter, realm string) {
+	rw.Header().Set("WWW-Authenticate", "Basic realm=\""+realm+"\", charset=\"UTF-8\"")
 	http.Error(rw, "Unauthorized", http.StatusUnauthorized)
 }
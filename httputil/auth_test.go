@@ -72,3 +72,109 @@ func TestSingleAuthHandler(t *testing.T) {
 		t.Error(resp.Header.Get("WWW-Authenticate"))
 	}
 }
+
+func TestMultiAuthHandler(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("test"))
+	})
+	users := map[string]string{
+		"User A": "Password A",
+		"User B": "Password B",
+		"User C": "Password C",
+	}
+	cases := []struct {
+		user, passwd string
+		anonymous    bool
+		status       int
+	}{
+		{anonymous: true, status: http.StatusUnauthorized},
+		{"User D", "Password A", false, http.StatusUnauthorized},
+		{"User B", "Password A", false, http.StatusUnauthorized},
+		{"User B", "", false, http.StatusUnauthorized},
+		{"User B", "Password B", false, http.StatusOK},
+		{"User C", "Password C", false, http.StatusOK},
+	}
+	handlers := []*MultiAuthHandler{
+		{Handler: h, Users: users, Realm: "The Realm"},
+		{Handler: h, Realm: "The Realm", CredentialChecker: func(user, passwd string) bool {
+			p, ok := users[user]
+			return ok && p == passwd
+		}},
+	}
+	for _, auth := range handlers {
+		srv := httptest.NewServer(auth)
+		for _, c := range cases {
+			req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+			if !c.anonymous {
+				req.SetBasicAuth(c.user, c.passwd)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, _ := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode != c.status {
+				t.Errorf("case %v: %d", c, resp.StatusCode)
+			}
+			if c.status == http.StatusOK {
+				if string(b) != "test" {
+					t.Errorf("case %v: %s", c, string(b))
+				}
+			} else if resp.Header.Get("WWW-Authenticate") != "Basic realm=\"The Realm\", charset=\"UTF-8\"" {
+				t.Errorf("case %v: %s", c, resp.Header.Get("WWW-Authenticate"))
+			}
+		}
+		srv.Close()
+	}
+}
+
+func TestBearerAuthHandler(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("test"))
+	})
+	cases := []struct {
+		auth   string
+		status int
+	}{
+		{"", http.StatusUnauthorized},
+		{"Basic dXNlcjpwYXNzd29yZA==", http.StatusUnauthorized},
+		{"Bearer", http.StatusUnauthorized},
+		{"Bearer ", http.StatusUnauthorized},
+		{"Bearer token-c", http.StatusUnauthorized},
+		{"Bearer token-a", http.StatusOK},
+		{"bearer token-b", http.StatusOK},
+	}
+	handlers := []*BearerAuthHandler{
+		{Handler: h, Tokens: []string{"token-a", "token-b"}},
+		{Handler: h, Validate: func(token string) bool {
+			return token == "token-a" || token == "token-b"
+		}},
+	}
+	for _, auth := range handlers {
+		srv := httptest.NewServer(auth)
+		for _, c := range cases {
+			req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+			if c.auth != "" {
+				req.Header.Set("Authorization", c.auth)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, _ := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode != c.status {
+				t.Errorf("case %q: %d", c.auth, resp.StatusCode)
+			}
+			if c.status == http.StatusOK {
+				if string(b) != "test" {
+					t.Errorf("case %q: %s", c.auth, string(b))
+				}
+			} else if resp.Header.Get("WWW-Authenticate") != "Bearer" {
+				t.Errorf("case %q: %s", c.auth, resp.Header.Get("WWW-Authenticate"))
+			}
+		}
+		srv.Close()
+	}
+}
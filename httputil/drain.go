@@ -0,0 +1,39 @@
+package httputil
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// DrainHandler wraps another http.Handler and rejects new requests with 503
+// Service Unavailable, while it is draining. Requests already in progress are
+// completed. Drain should be called before shutting down the HTTP server.
+type DrainHandler struct {
+	http.Handler
+
+	draining int32
+}
+
+// Drain starts rejecting new requests.
+func (h *DrainHandler) Drain() {
+	atomic.StoreInt32(&h.draining, 1)
+}
+
+// Resume accepts new requests again.
+func (h *DrainHandler) Resume() {
+	atomic.StoreInt32(&h.draining, 0)
+}
+
+// Draining returns true, if new requests are rejected.
+func (h *DrainHandler) Draining() bool {
+	return atomic.LoadInt32(&h.draining) != 0
+}
+
+func (h *DrainHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if h.Draining() {
+		rw.Header().Set("Connection", "close")
+		http.Error(rw, "Service Unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	h.Handler.ServeHTTP(rw, req)
+}
@@ -0,0 +1,71 @@
+package httputil
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDrainHandler(t *testing.T) {
+	started := make(chan struct{})
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			close(started)
+			time.Sleep(100 * time.Millisecond)
+		}
+		w.Write([]byte("test"))
+	})
+	drain := &DrainHandler{Handler: h}
+	srv := httptest.NewServer(drain)
+	defer srv.Close()
+
+	get := func(path string) (int, string) {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		b, _ := ioutil.ReadAll(resp.Body)
+		return resp.StatusCode, string(b)
+	}
+
+	if code, body := get("/"); code != http.StatusOK || body != "test" {
+		t.Error(code, body)
+	}
+
+	// request in progress is completed
+	slow := make(chan int)
+	go func() {
+		resp, err := http.Get(srv.URL + "/slow")
+		if err != nil {
+			slow <- 0
+			return
+		}
+		resp.Body.Close()
+		slow <- resp.StatusCode
+	}()
+	<-started
+	drain.Drain()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Error(resp.StatusCode)
+	}
+	if !resp.Close {
+		t.Error("expected Connection: close")
+	}
+	if code := <-slow; code != http.StatusOK {
+		t.Error(code)
+	}
+
+	drain.Resume()
+	if code, body := get("/"); code != http.StatusOK || body != "test" {
+		t.Error(code, body)
+	}
+}
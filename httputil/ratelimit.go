@@ -0,0 +1,136 @@
+package httputil
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultRateLimitIdleTimeout = 10 * time.Minute
+
+// RateLimitHandler wraps another http.Handler and limits the request rate per
+// client with a token bucket. Requests exceeding the limit are answered with
+// 429 Too Many Requests. A RateLimitHandler must not be copied after first use.
+type RateLimitHandler struct {
+	http.Handler
+
+	// Rate is the number of requests per second. Default is 1, if not
+	// positive.
+	Rate float64
+	// Burst is the maximum number of requests at once. Default is 1, if not
+	// positive.
+	Burst int
+	// Clients are identified by their IP address. If KeyHeader is not empty
+	// (e.g. X-Forwarded-For behind a proxy), this header is used instead, if
+	// present. The header is only trustworthy, if all requests pass the trusted
+	// proxies, because clients can send arbitrary values. Each proxy appends
+	// the address of its peer on the right side of the comma separated list.
+	KeyHeader string
+	// TrustedProxies is the number of trusted proxies in front of the server,
+	// which append to KeyHeader. The entry added by the outermost trusted proxy
+	// is used as key. Entries on the left of it are chosen by the client and
+	// ignored. Default is 1, if not positive.
+	TrustedProxies int
+	// Buckets of clients, which are idle for this duration, are removed.
+	// Default is 10 minutes.
+	IdleTimeout time.Duration
+
+	mtx       sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastEvict time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func (h *RateLimitHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	wait := h.take(h.key(req), time.Now())
+	if wait > 0 {
+		rw.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		http.Error(rw, "Too Many Requests", http.StatusTooManyRequests)
+		return
+	}
+	h.Handler.ServeHTTP(rw, req)
+}
+
+func (h *RateLimitHandler) key(req *http.Request) string {
+	if h.KeyHeader != "" {
+		if v := req.Header.Get(h.KeyHeader); v != "" {
+			// X-Forwarded-For contains a list of addresses, use the entry
+			// added by the outermost trusted proxy
+			vs := strings.Split(v, ",")
+			n := h.TrustedProxies
+			if n <= 0 {
+				n = 1
+			}
+			idx := len(vs) - n
+			if idx < 0 {
+				idx = 0
+			}
+			return strings.TrimSpace(vs[idx])
+		}
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+// take removes a token from the bucket of the client. If no token is
+// available, the duration until the next token is available is returned.
+func (h *RateLimitHandler) take(key string, now time.Time) time.Duration {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	h.evict(now)
+
+	rate := h.Rate
+	if rate <= 0 {
+		rate = 1
+	}
+	burst := float64(h.Burst)
+	if burst <= 0 {
+		burst = 1
+	}
+	b, ok := h.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: burst, last: now}
+		h.buckets[key] = b
+	}
+	// refill bucket
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / rate * float64(time.Second))
+}
+
+// evict removes idle buckets. The mutex must be locked.
+func (h *RateLimitHandler) evict(now time.Time) {
+	if h.buckets == nil {
+		h.buckets = make(map[string]*tokenBucket)
+		h.lastEvict = now
+		return
+	}
+	idle := h.IdleTimeout
+	if idle <= 0 {
+		idle = defaultRateLimitIdleTimeout
+	}
+	if now.Sub(h.lastEvict) < idle {
+		return
+	}
+	for k, b := range h.buckets {
+		if now.Sub(b.last) >= idle {
+			delete(h.buckets, k)
+		}
+	}
+	h.lastEvict = now
+}
@@ -0,0 +1,164 @@
+package httputil
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimitHandler(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("test"))
+	})
+	limit := &RateLimitHandler{
+		Handler:   h,
+		Rate:      1,
+		Burst:     2,
+		KeyHeader: "X-Forwarded-For",
+	}
+	srv := httptest.NewServer(limit)
+	defer srv.Close()
+
+	get := func(fwd string) *http.Response {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+		if fwd != "" {
+			req.Header.Set("X-Forwarded-For", fwd)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	// burst
+	for i := 0; i < 2; i++ {
+		if resp := get(""); resp.StatusCode != http.StatusOK {
+			t.Fatal(i, resp.StatusCode)
+		}
+	}
+	resp := get("")
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatal(resp.StatusCode)
+	}
+	if resp.Header.Get("Retry-After") != "1" {
+		t.Error(resp.Header.Get("Retry-After"))
+	}
+
+	// other client behind proxy
+	if resp := get("10.0.0.1, 192.0.2.1"); resp.StatusCode != http.StatusOK {
+		t.Error(resp.StatusCode)
+	}
+}
+
+func TestRateLimitHandlerRefill(t *testing.T) {
+	h := &RateLimitHandler{Rate: 2, Burst: 2}
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		if wait := h.take("a", now); wait != 0 {
+			t.Fatal(i, wait)
+		}
+	}
+	if wait := h.take("a", now); wait != 500*time.Millisecond {
+		t.Fatal(wait)
+	}
+	// one token after 500ms
+	if wait := h.take("a", now.Add(500*time.Millisecond)); wait != 0 {
+		t.Fatal(wait)
+	}
+	if wait := h.take("a", now.Add(500*time.Millisecond)); wait != 500*time.Millisecond {
+		t.Fatal(wait)
+	}
+	// bucket is not filled beyond the burst
+	now = now.Add(time.Hour)
+	for i := 0; i < 2; i++ {
+		if wait := h.take("a", now); wait != 0 {
+			t.Fatal(i, wait)
+		}
+	}
+	if wait := h.take("a", now); wait == 0 {
+		t.Fatal("expected limit")
+	}
+}
+
+func TestRateLimitHandlerSpoofedHeader(t *testing.T) {
+	h := &RateLimitHandler{Rate: 1, Burst: 1, KeyHeader: "X-Forwarded-For"}
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		// the leading entry is chosen by the client
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Forwarded-For", fmt.Sprintf("1.1.1.%d, 9.9.9.9", i))
+		if k := h.key(req); k != "9.9.9.9" {
+			t.Fatal(k)
+		}
+		wait := h.take(h.key(req), now)
+		if i == 0 && wait != 0 || i > 0 && wait <= 0 {
+			t.Fatal(i, wait)
+		}
+	}
+	if len(h.buckets) != 1 {
+		t.Fatal(h.buckets)
+	}
+
+	// two trusted proxies
+	h = &RateLimitHandler{KeyHeader: "X-Forwarded-For", TrustedProxies: 2}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Forwarded-For", "1.1.1.1, 8.8.8.8, 10.0.0.1")
+	if k := h.key(req); k != "8.8.8.8" {
+		t.Error(k)
+	}
+	req.Header.Set("X-Forwarded-For", "8.8.8.8")
+	if k := h.key(req); k != "8.8.8.8" {
+		t.Error(k)
+	}
+}
+
+func TestRateLimitHandlerDefaults(t *testing.T) {
+	cases := []struct {
+		rate  float64
+		burst int
+	}{
+		{0, 2},
+		{-1, 2},
+		{1, 0},
+		{1, -1},
+		{0, 0},
+	}
+	for _, c := range cases {
+		h := &RateLimitHandler{Rate: c.rate, Burst: c.burst}
+		burst := c.burst
+		if burst <= 0 {
+			burst = 1
+		}
+		now := time.Now()
+		for i := 0; i < burst; i++ {
+			if wait := h.take("a", now); wait != 0 {
+				t.Fatalf("case %v: request %d: %v", c, i, wait)
+			}
+		}
+		// default rate is 1 request per second
+		if wait := h.take("a", now); wait != time.Second {
+			t.Fatalf("case %v: %v", c, wait)
+		}
+		if wait := h.take("a", now.Add(time.Second)); wait != 0 {
+			t.Fatalf("case %v: %v", c, wait)
+		}
+	}
+}
+
+func TestRateLimitHandlerEvict(t *testing.T) {
+	h := &RateLimitHandler{Rate: 1, Burst: 1, IdleTimeout: time.Minute}
+	now := time.Now()
+	h.take("a", now)
+	h.take("b", now.Add(30*time.Second))
+	if len(h.buckets) != 2 {
+		t.Fatal(len(h.buckets))
+	}
+	h.take("b", now.Add(90*time.Second))
+	if _, ok := h.buckets["a"]; ok || len(h.buckets) != 1 {
+		t.Fatal(h.buckets)
+	}
+}
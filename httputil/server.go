@@ -2,6 +2,7 @@ package httputil
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
 	"time"
@@ -22,11 +23,21 @@ type Server struct {
 	CertFile string
 	// Private key file for HTTPS
 	KeyFile string
+	// TLS configuration for HTTPS. Go defaults are used, if not specified.
+	TLSConfig *tls.Config
+	// Minimum TLS version for HTTPS (e.g. tls.VersionTLS12), if not zero.
+	// Overrides TLSConfig.MinVersion.
+	MinTLSVersion uint16
 	// When an error happens while serving (e.g. binding of port fails), this
 	// error is sent to the channel ServeErr.
 	ServeErr chan<- error
 	// Default logger is "http-server", if not specified
 	Log logging.Logger
+	// Timeouts for both servers (see http.Server). Zero means no timeout.
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
 
 	done      chan struct{}
 	server    http.Server
@@ -38,6 +49,21 @@ func (s *Server) Startup() {
 	// setup
 	s.server.Addr = s.Addr
 	s.serverTLS.Addr = s.AddrTLS
+	for _, svr := range []*http.Server{&s.server, &s.serverTLS} {
+		svr.ReadTimeout = s.ReadTimeout
+		svr.ReadHeaderTimeout = s.ReadHeaderTimeout
+		svr.WriteTimeout = s.WriteTimeout
+		svr.IdleTimeout = s.IdleTimeout
+	}
+	if s.TLSConfig != nil {
+		s.serverTLS.TLSConfig = s.TLSConfig.Clone()
+	}
+	if s.MinTLSVersion != 0 {
+		if s.serverTLS.TLSConfig == nil {
+			s.serverTLS.TLSConfig = &tls.Config{}
+		}
+		s.serverTLS.TLSConfig.MinVersion = s.MinTLSVersion
+	}
 	// capacity of 2 to avoid blocking, when shutting down
 	s.done = make(chan struct{}, 2)
 	if s.Log == nil {
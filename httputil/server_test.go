@@ -0,0 +1,119 @@
+package httputil
+
+import (
+	"bufio"
+	"crypto/tls"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// freeAddr returns a currently unused local address.
+func freeAddr(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
+
+// dial connects to the specified address and waits for the server startup.
+func dial(t *testing.T, addr string) net.Conn {
+	for i := 0; i < 50; i++ {
+		conn, err := net.Dial("tcp", addr)
+		if err == nil {
+			return conn
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("Server not reachable: " + addr)
+	return nil
+}
+
+func TestServerIdleTimeout(t *testing.T) {
+	srv := &Server{
+		Addr:        freeAddr(t),
+		IdleTimeout: 100 * time.Millisecond,
+	}
+	srv.Startup()
+	defer srv.Shutdown()
+
+	conn := dial(t, srv.Addr)
+	defer conn.Close()
+
+	// keep-alive request
+	_, err := io.WriteString(conn, "GET / HTTP/1.1\r\nHost: localhost\r\n\r\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	// connection must be closed by the server after the idle timeout
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, err = r.ReadByte()
+	if err != io.EOF {
+		t.Fatal("Expected closed connection: ", err)
+	}
+}
+
+func TestServerMinTLSVersion(t *testing.T) {
+	dir := t.TempDir()
+	gen := &CertGenerator{
+		Hosts:          []string{"127.0.0.1"},
+		Organization:   "Test",
+		NotBefore:      time.Now().Add(-time.Hour),
+		NotAfter:       time.Now().Add(time.Hour),
+		CACertFile:     filepath.Join(dir, "ca.crt"),
+		CAKeyFile:      filepath.Join(dir, "ca.key"),
+		ServerCertFile: filepath.Join(dir, "server.crt"),
+		ServerKeyFile:  filepath.Join(dir, "server.key"),
+	}
+	if err := gen.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		min, client uint16
+		ok          bool
+	}{
+		{tls.VersionTLS12, tls.VersionTLS10, false},
+		{tls.VersionTLS12, tls.VersionTLS12, true},
+		{tls.VersionTLS13, tls.VersionTLS12, false},
+		{tls.VersionTLS13, tls.VersionTLS13, true},
+	}
+	for _, c := range cases {
+		srv := &Server{
+			AddrTLS:       freeAddr(t),
+			CertFile:      gen.ServerCertFile,
+			KeyFile:       gen.ServerKeyFile,
+			TLSConfig:     &tls.Config{},
+			MinTLSVersion: c.min,
+		}
+		srv.Startup()
+		conn := tls.Client(dial(t, srv.AddrTLS), &tls.Config{
+			InsecureSkipVerify: true,
+			MinVersion:         c.client,
+			MaxVersion:         c.client,
+		})
+		err := conn.Handshake()
+		conn.Close()
+		srv.Shutdown()
+		if c.ok && err != nil {
+			t.Errorf("min %x, client %x: %v", c.min, c.client, err)
+		}
+		if !c.ok && err == nil {
+			t.Errorf("min %x, client %x: expected handshake error", c.min, c.client)
+		}
+	}
+}
@@ -3,11 +3,23 @@ package releng
 import (
 	"os"
 	"os/exec"
+	"strings"
 )
 
 // GoSpec configures a Go binary build.
 type GoSpec struct {
 	OS, Arch, Arm, LDFlags string
+	// Strip removes the symbol table and the debug information from the binary
+	// (linker flags -s -w).
+	Strip bool
+}
+
+// ldFlags returns the linker flags for the build.
+func (s GoSpec) ldFlags() string {
+	if !s.Strip {
+		return s.LDFlags
+	}
+	return strings.TrimSpace(s.LDFlags + " -s -w")
 }
 
 // BuildGo builds a Go binary.
@@ -18,9 +30,9 @@ func BuildGo(pkg, dest string, spec GoSpec) {
 	log.Debug("OS: ", spec.OS)
 	log.Debug("Arch: ", spec.Arch)
 	log.Debug("Arm: ", spec.Arm)
-	log.Debug("LDFlags: ", spec.LDFlags)
+	log.Debug("LDFlags: ", spec.ldFlags())
 
-	cmd := exec.Command("go", "build", "-o", dest, "-trimpath", "-ldflags="+spec.LDFlags, pkg)
+	cmd := exec.Command("go", "build", "-o", dest, "-trimpath", "-ldflags="+spec.ldFlags(), pkg)
 	env := os.Environ()
 	env = append(env, "GOOS="+spec.OS, "GOARCH="+spec.Arch, "GOARM="+spec.Arm)
 	cmd.Env = env
@@ -33,4 +45,9 @@ func BuildGo(pkg, dest string, spec GoSpec) {
 		}
 	}
 	Must(err)
+
+	// report binary size
+	inf, err := os.Stat(dest)
+	Must(err)
+	log.Info("Binary size: ", inf.Size())
 }
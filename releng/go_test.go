@@ -0,0 +1,20 @@
+package releng
+
+import "testing"
+
+func TestGoSpecLDFlags(t *testing.T) {
+	cases := []struct {
+		spec GoSpec
+		exp  string
+	}{
+		{GoSpec{}, ""},
+		{GoSpec{LDFlags: "-X main.version=1.0"}, "-X main.version=1.0"},
+		{GoSpec{Strip: true}, "-s -w"},
+		{GoSpec{LDFlags: "-X main.version=1.0", Strip: true}, "-X main.version=1.0 -s -w"},
+	}
+	for _, c := range cases {
+		if act := c.spec.ldFlags(); act != c.exp {
+			t.Errorf("case %#v: %s", c.spec, act)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package releng
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// Artifact describes a release artifact.
+type Artifact struct {
+	// File path of the artifact
+	File string
+	// Platform of the artifact, e.g. linux-amd64
+	Platform string
+}
+
+// manifestEntry is a JSON entry of the release manifest.
+type manifestEntry struct {
+	Name     string `json:"name"`
+	Size     int64  `json:"size"`
+	Platform string `json:"platform"`
+	SHA256   string `json:"sha256"`
+}
+
+// ReleaseManifest writes the files SHA256SUMS and manifest.json for the
+// specified artifacts into the output directory.
+func ReleaseManifest(artifacts []Artifact, outDir string) {
+	log.Info("Building release manifest: ", outDir)
+	var sums bytes.Buffer
+	entries := make([]manifestEntry, 0, len(artifacts))
+	for _, a := range artifacts {
+		log.Debug("Adding: ", a.File)
+		name := path.Base(filepath.ToSlash(a.File))
+		sum, size := hashFile(a.File)
+		fmt.Fprintf(&sums, "%s  %s\n", sum, name)
+		entries = append(entries, manifestEntry{
+			Name:     name,
+			Size:     size,
+			Platform: a.Platform,
+			SHA256:   sum,
+		})
+	}
+	WriteFile(filepath.Join(outDir, "SHA256SUMS"), sums.Bytes())
+	m, err := json.MarshalIndent(entries, "", "  ")
+	Must(err)
+	WriteFile(filepath.Join(outDir, "manifest.json"), m)
+}
+
+// hashFile returns the hex encoded SHA256 checksum and the size of a file.
+func hashFile(file string) (string, int64) {
+	f, err := os.Open(file)
+	Must(err)
+	defer f.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	Must(err)
+	return hex.EncodeToString(h.Sum(nil)), n
+}
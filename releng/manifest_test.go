@@ -0,0 +1,46 @@
+package releng
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/mdzio/go-lib/jsonutil"
+)
+
+func TestReleaseManifest(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "app-linux-amd64.tgz")
+	b := filepath.Join(dir, "app-windows-amd64.zip")
+	WriteFile(a, []byte("abc"))
+	WriteFile(b, []byte(""))
+
+	ReleaseManifest([]Artifact{
+		{File: a, Platform: "linux-amd64"},
+		{File: b, Platform: "windows-amd64"},
+	}, dir)
+
+	sums, err := ioutil.ReadFile(filepath.Join(dir, "SHA256SUMS"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad  app-linux-amd64.tgz\n" +
+		"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855  app-windows-amd64.zip\n"
+	if string(sums) != exp {
+		t.Error(string(sums))
+	}
+
+	m, err := ioutil.ReadFile(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp = `[
+		{"name":"app-linux-amd64.tgz","size":3,"platform":"linux-amd64",
+		 "sha256":"ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{"name":"app-windows-amd64.zip","size":0,"platform":"windows-amd64",
+		 "sha256":"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"}
+	]`
+	if !jsonutil.Equal(m, []byte(exp)) {
+		t.Error(string(m))
+	}
+}